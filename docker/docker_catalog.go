@@ -0,0 +1,112 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/containers/image/types"
+	"github.com/pkg/errors"
+)
+
+// catalogPath is the well-known registry-wide repository enumeration endpoint, see
+// https://docs.docker.com/registry/spec/api/#listing-repositories.
+const catalogPath = "/v2/_catalog"
+
+// CatalogOptions controls pagination for ListRepositories and ListRepositoriesIterate.
+type CatalogOptions struct {
+	// N is the page size requested from the registry (the "n" query parameter). Zero lets
+	// the registry pick its own default.
+	N int
+	// Last is the pagination cursor: the name of the last repository seen on a previous
+	// call, corresponding to the "last" query parameter. Empty starts from the beginning.
+	Last string
+}
+
+// catalogRes is the JSON body of a GET /v2/_catalog response.
+type catalogRes struct {
+	Repositories []string
+}
+
+// makeCatalogRequest makes a single request to the _catalog endpoint given an input path, and
+// returns the repository names and any Link header found, for pagination by the caller.
+func makeCatalogRequest(ctx context.Context, c *dockerClient, path string) ([]string, []string, error) {
+	res, err := c.makeRequest(ctx, "GET", path, nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, nil, errors.Errorf("Invalid status code returned when fetching repository catalog %d", res.StatusCode)
+	}
+
+	repos := &catalogRes{}
+	if err := json.NewDecoder(res.Body).Decode(repos); err != nil {
+		return nil, nil, err
+	}
+
+	return repos.Repositories, (res.Header)["Link"], nil
+}
+
+// ListRepositories returns the aggregated list of repository names held in registry, by
+// enumerating GET /v2/_catalog and following the Link-header pagination the distribution spec
+// defines, starting from opts.Last/opts.N if set.
+func ListRepositories(ctx context.Context, sys *types.SystemContext, registry string, opts *CatalogOptions) ([]string, error) {
+	var result []string
+	err := ListRepositoriesIterate(ctx, sys, registry, opts, func(page []string) error {
+		result = append(result, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ListRepositoriesIterate enumerates the repository catalog of registry page by page, calling fn
+// with each page. It stops as soon as fn returns an error, without fetching further pages.
+func ListRepositoriesIterate(ctx context.Context, sys *types.SystemContext, registry string, opts *CatalogOptions, fn func(repositories []string) error) error {
+	if opts == nil {
+		opts = &CatalogOptions{}
+	}
+
+	c, err := newDockerClient(sys, registry)
+	if err != nil {
+		return errors.Wrapf(err, "Error establishing connection to registry %s", registry)
+	}
+
+	path := catalogPath
+	query := url.Values{}
+	if opts.N > 0 {
+		query.Set("n", strconv.Itoa(opts.N))
+	}
+	if opts.Last != "" {
+		query.Set("last", opts.Last)
+	}
+	if encoded := query.Encode(); encoded != "" {
+		path = fmt.Sprintf("%s?%s", path, encoded)
+	}
+
+	for {
+		repos, linkValue, err := makeCatalogRequest(ctx, c, path)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(repos); err != nil {
+			return err
+		}
+
+		next, ok, err := nextPageFromLinkHeader(linkValue)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		path = next
+	}
+}
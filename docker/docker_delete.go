@@ -0,0 +1,61 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/containers/image/docker/reference"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+// ErrManifestNotFound is returned by DeleteTag and DeleteManifest when the registry reports
+// that the tag or manifest digest being deleted does not exist.
+var ErrManifestNotFound = errors.New("manifest not found")
+
+// ErrRegistryDeleteDisabled is returned by DeleteTag and DeleteManifest when the registry
+// responds that it has manifest deletion disabled (HTTP 405, see the distribution spec).
+var ErrRegistryDeleteDisabled = errors.New("registry has manifest deletion disabled")
+
+// DeleteTag resolves tag to its current manifest digest and deletes that manifest from the
+// repository. Deleting a manifest removes every tag pointing at it, not just tag.
+func (i *Image) DeleteTag(ctx context.Context, tag string) error {
+	info, err := headManifestForTag(ctx, i.src.c, reference.Path(i.src.ref.ref), tag, ErrRegistryDeleteDisabled)
+	if err != nil {
+		return err
+	}
+	if info.Digest == "" {
+		return errors.Errorf("registry did not report a Docker-Content-Digest for tag %q", tag)
+	}
+	return i.DeleteManifest(ctx, info.Digest)
+}
+
+// DeleteManifest deletes the manifest identified by dgst, and with it every tag pointing at it.
+// It returns ErrManifestNotFound if the manifest doesn't exist, and ErrRegistryDeleteDisabled if
+// the registry has manifest deletion turned off.
+func (i *Image) DeleteManifest(ctx context.Context, dgst digest.Digest) error {
+	path := fmt.Sprintf(manifestPath, reference.Path(i.src.ref.ref), dgst.String())
+	res, err := i.src.c.makeRequest(ctx, "DELETE", path, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	return classifyDeleteManifestStatus(res.StatusCode, dgst)
+}
+
+// classifyDeleteManifestStatus maps the status code of a DELETE /v2/<name>/manifests/<digest>
+// response to the error DeleteManifest promises for it, or nil on success.
+func classifyDeleteManifestStatus(statusCode int, dgst digest.Digest) error {
+	switch statusCode {
+	case http.StatusAccepted:
+		return nil
+	case http.StatusNotFound:
+		return ErrManifestNotFound
+	case http.StatusMethodNotAllowed:
+		return ErrRegistryDeleteDisabled
+	default:
+		return errors.Errorf("Invalid status code returned when deleting manifest %s: %d", dgst, statusCode)
+	}
+}
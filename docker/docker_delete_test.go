@@ -0,0 +1,75 @@
+package docker
+
+import (
+	"net/http"
+	"testing"
+
+	digest "github.com/opencontainers/go-digest"
+)
+
+func TestClassifyDeleteManifestStatus(t *testing.T) {
+	const dgst = digest.Digest("sha256:2cf3f4ce83d6ea77dd6930a3a8b52bf1d32305caeb5f5c3ab9d06a5e4e6d6c5e")
+
+	for _, c := range []struct {
+		name       string
+		statusCode int
+		wantErr    error
+	}{
+		{"accepted", http.StatusAccepted, nil},
+		{"not found", http.StatusNotFound, ErrManifestNotFound},
+		{"delete disabled", http.StatusMethodNotAllowed, ErrRegistryDeleteDisabled},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			err := classifyDeleteManifestStatus(c.statusCode, dgst)
+			if err != c.wantErr {
+				t.Errorf("classifyDeleteManifestStatus(%d) = %v, want %v", c.statusCode, err, c.wantErr)
+			}
+		})
+	}
+
+	t.Run("unexpected status", func(t *testing.T) {
+		err := classifyDeleteManifestStatus(http.StatusInternalServerError, dgst)
+		if err == nil {
+			t.Fatal("expected a non-nil error for an unexpected status code")
+		}
+		if err == ErrManifestNotFound || err == ErrRegistryDeleteDisabled {
+			t.Errorf("unexpected status code should not map to a typed error, got %v", err)
+		}
+	})
+}
+
+func TestClassifyHeadManifestStatus(t *testing.T) {
+	for _, c := range []struct {
+		name       string
+		statusCode int
+		wantErr    error
+	}{
+		{"ok", http.StatusOK, nil},
+		{"not found", http.StatusNotFound, ErrManifestNotFound},
+		{"head unsupported", http.StatusMethodNotAllowed, ErrManifestHeadUnsupported},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			err := classifyHeadManifestStatus(c.statusCode, "latest", ErrManifestHeadUnsupported)
+			if err != c.wantErr {
+				t.Errorf("classifyHeadManifestStatus(%d) = %v, want %v", c.statusCode, err, c.wantErr)
+			}
+		})
+	}
+
+	t.Run("405 uses the caller-supplied error", func(t *testing.T) {
+		err := classifyHeadManifestStatus(http.StatusMethodNotAllowed, "latest", ErrRegistryDeleteDisabled)
+		if err != ErrRegistryDeleteDisabled {
+			t.Errorf("classifyHeadManifestStatus(405) = %v, want %v", err, ErrRegistryDeleteDisabled)
+		}
+	})
+
+	t.Run("unexpected status", func(t *testing.T) {
+		err := classifyHeadManifestStatus(http.StatusInternalServerError, "latest", ErrManifestHeadUnsupported)
+		if err == nil {
+			t.Fatal("expected a non-nil error for an unexpected status code")
+		}
+		if err == ErrManifestNotFound || err == ErrManifestHeadUnsupported {
+			t.Errorf("unexpected status code should not map to a typed error, got %v", err)
+		}
+	})
+}
@@ -6,7 +6,11 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"path"
 	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/containers/image/docker/reference"
 	"github.com/containers/image/image"
@@ -42,14 +46,21 @@ func (i *Image) SourceRefFullName() string {
 }
 
 // MakeRepositoryTagsRequest make a single request to get tag listing given an input path.  Pagination is handled in the GetRepositoryTags outer function.
+//
+// Deprecated: use MakeRepositoryTagsRequestWithContext instead.
 func MakeRepositoryTagsRequest(i *Image, path string) ([]string, []string, error) {
+	return MakeRepositoryTagsRequestWithContext(context.Background(), i, path)
+}
+
+// MakeRepositoryTagsRequestWithContext makes a single request to get tag listing given an input path, using ctx
+// for cancellation and deadlines. Pagination is handled in the GetRepositoryTagsWithContext outer function.
+func MakeRepositoryTagsRequestWithContext(ctx context.Context, i *Image, path string) ([]string, []string, error) {
 	type tagsRes struct {
 		Tags []string
 	}
 	tags := &tagsRes{}
 
-	// FIXME: Pass the context.Context
-	res, err := i.src.c.makeRequest(context.TODO(), "GET", path, nil, nil)
+	res, err := i.src.c.makeRequest(ctx, "GET", path, nil, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -69,42 +80,178 @@ func MakeRepositoryTagsRequest(i *Image, path string) ([]string, []string, error
 }
 
 // GetRepositoryTags list all tags available in the repository. Note that this has no connection with the tag(s) used for this specific image, if any.
+//
+// Deprecated: use GetRepositoryTagsWithContext instead.
 func (i *Image) GetRepositoryTags() ([]string, error) {
-	var result []string
+	return i.GetRepositoryTagsWithContext(context.Background())
+}
 
-	done := false
-	nextLinkRegexp := regexp.MustCompile(`\A<(.+)>;(.+)\z`)
+// GetRepositoryTagsWithContext list all tags available in the repository. Note that this has no connection with the tag(s) used for this specific image, if any.
+// ctx can be used to cancel the (possibly paginated) listing or attach deadlines/tracing.
+func (i *Image) GetRepositoryTagsWithContext(ctx context.Context) ([]string, error) {
+	var result []string
 
 	path := fmt.Sprintf(tagsPath, reference.Path(i.src.ref.ref))
 
-	for !done {
-		tags, linkValue, err := MakeRepositoryTagsRequest(i, path)
+	for {
+		tags, linkValue, err := MakeRepositoryTagsRequestWithContext(ctx, i, path)
 		if tags == nil {
 			return nil, err
 		}
 
 		result = append(result, tags...)
 
-		if len(linkValue) < 1 {
-			// no Link header found indicating pagination is done
-			done = true
-		} else {
-			// got a Link header in response, indicating pagination is enabled - parse the path and continue
-
-			match := nextLinkRegexp.FindStringSubmatch(linkValue[0])
-			if match != nil {
-				u, uerr := url.Parse(match[1])
-				if uerr != nil {
-					return nil, uerr
-				} else {
-					path = fmt.Sprintf("%s?%s", u.Path, u.RawQuery)
-				}
-			} else {
-				return nil, errors.Errorf("Could not parse link header in response when fetching tags list")
+		next, ok, err := nextPageFromLinkHeader(linkValue)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		path = next
+	}
+
+	return result, nil
+}
+
+// GetRepositoryTagsOptions controls pagination, filtering and ordering for Image.ListRepositoryTags.
+type GetRepositoryTagsOptions struct {
+	// N is the page size requested from the registry (the "n" query parameter of
+	// GET /v2/<name>/tags/list). Zero lets the registry pick its own default.
+	N int
+	// Last is the pagination cursor: the name of the last tag seen on a previous call,
+	// corresponding to the "last" query parameter. Empty starts from the beginning.
+	Last string
+	// Filter, if set, is matched against every tag name with regexp.MatchString, so it is
+	// unanchored (a match anywhere in the name counts); tags that don't match are dropped.
+	Filter *regexp.Regexp
+	// Glob, if set, is matched against every tag name using path.Match's shell pattern
+	// syntax (e.g. "v1.*", "release-[0-9]*"); tags that don't match are dropped from the
+	// result. Filter and Glob can both be set, in which case a tag must satisfy both.
+	Glob string
+	// SortSemver, if true, parses tag names as semantic versions (tolerating a leading "v"
+	// and missing minor/patch components) and orders the result newest-first. Tags that
+	// don't parse as a version sort after the ones that do, in lexical order.
+	SortSemver bool
+}
+
+// matches reports whether tag satisfies opts.Filter and opts.Glob (whichever of them are set).
+func (opts GetRepositoryTagsOptions) matches(tag string) (bool, error) {
+	if opts.Filter != nil && !opts.Filter.MatchString(tag) {
+		return false, nil
+	}
+	if opts.Glob != "" {
+		matched, err := path.Match(opts.Glob, tag)
+		if err != nil {
+			return false, errors.Wrapf(err, "matching tag %q against glob %q", tag, opts.Glob)
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// ListRepositoryTags lists tags available in the repository, honoring opts.N/opts.Last as the
+// starting page of the distribution /tags/list API, following any further Link-header
+// pagination the registry reports, and applying opts.Filter and opts.SortSemver to the result.
+func (i *Image) ListRepositoryTags(ctx context.Context, opts GetRepositoryTagsOptions) ([]string, error) {
+	var result []string
+
+	path := fmt.Sprintf(tagsPath, reference.Path(i.src.ref.ref))
+	query := url.Values{}
+	if opts.N > 0 {
+		query.Set("n", strconv.Itoa(opts.N))
+	}
+	if opts.Last != "" {
+		query.Set("last", opts.Last)
+	}
+	if encoded := query.Encode(); encoded != "" {
+		path = fmt.Sprintf("%s?%s", path, encoded)
+	}
+
+	for {
+		tags, linkValue, err := MakeRepositoryTagsRequestWithContext(ctx, i, path)
+		if tags == nil {
+			return nil, err
+		}
+
+		for _, tag := range tags {
+			matched, err := opts.matches(tag)
+			if err != nil {
+				return nil, err
+			}
+			if !matched {
+				continue
 			}
+			result = append(result, tag)
+		}
+
+		next, ok, err := nextPageFromLinkHeader(linkValue)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
 		}
+		path = next
+	}
 
+	if opts.SortSemver {
+		sortTagsBySemverNewestFirst(result)
 	}
 
 	return result, nil
 }
+
+// parsedSemver is a tolerant parse of a tag name as a semantic version: a leading "v" is
+// accepted and stripped, and missing minor/patch components default to 0.
+type parsedSemver struct {
+	major, minor, patch int
+}
+
+// parseSemverTolerant parses s as a semver-like version, accepting a leading "v" and missing
+// minor/patch components (e.g. "v2", "1.4", "1.4.3"). It does not attempt to parse
+// pre-release or build metadata suffixes; ok is false if the numeric core can't be parsed.
+func parseSemverTolerant(s string) (v parsedSemver, ok bool) {
+	s = strings.TrimPrefix(s, "v")
+	if i := strings.IndexAny(s, "-+"); i >= 0 {
+		s = s[:i]
+	}
+	parts := strings.SplitN(s, ".", 3)
+	nums := make([]int, 3)
+	for idx, part := range parts {
+		if part == "" {
+			return parsedSemver{}, false
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return parsedSemver{}, false
+		}
+		nums[idx] = n
+	}
+	return parsedSemver{major: nums[0], minor: nums[1], patch: nums[2]}, true
+}
+
+// sortTagsBySemverNewestFirst sorts tags in place, newest semantic version first. Tags that
+// don't parse as a version are placed after all the ones that do, in lexical order.
+func sortTagsBySemverNewestFirst(tags []string) {
+	sort.SliceStable(tags, func(a, b int) bool {
+		va, aok := parseSemverTolerant(tags[a])
+		vb, bok := parseSemverTolerant(tags[b])
+		switch {
+		case aok && !bok:
+			return true
+		case !aok && bok:
+			return false
+		case !aok && !bok:
+			return tags[a] < tags[b]
+		case va.major != vb.major:
+			return va.major > vb.major
+		case va.minor != vb.minor:
+			return va.minor > vb.minor
+		default:
+			return va.patch > vb.patch
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package docker
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestParseSemverTolerant(t *testing.T) {
+	for _, c := range []struct {
+		in     string
+		want   parsedSemver
+		wantOK bool
+	}{
+		{"1.2.3", parsedSemver{1, 2, 3}, true},
+		{"v1.2.3", parsedSemver{1, 2, 3}, true},
+		{"v2", parsedSemver{2, 0, 0}, true},
+		{"1.4", parsedSemver{1, 4, 0}, true},
+		{"1.2.3-rc1", parsedSemver{1, 2, 3}, true},
+		{"1.2.3+build5", parsedSemver{1, 2, 3}, true},
+		{"latest", parsedSemver{}, false},
+		{"1.x.3", parsedSemver{}, false},
+		{"", parsedSemver{}, false},
+	} {
+		got, ok := parseSemverTolerant(c.in)
+		if ok != c.wantOK {
+			t.Errorf("parseSemverTolerant(%q) ok = %v, want %v", c.in, ok, c.wantOK)
+			continue
+		}
+		if ok && got != c.want {
+			t.Errorf("parseSemverTolerant(%q) = %+v, want %+v", c.in, got, c.want)
+		}
+	}
+}
+
+func TestSortTagsBySemverNewestFirst(t *testing.T) {
+	tags := []string{"v1.2.0", "latest", "v1.10.0", "v1.2.10", "v2.0.0", "nightly"}
+	sortTagsBySemverNewestFirst(tags)
+
+	want := []string{"v2.0.0", "v1.10.0", "v1.2.10", "v1.2.0", "latest", "nightly"}
+	if !reflect.DeepEqual(tags, want) {
+		t.Errorf("sortTagsBySemverNewestFirst() = %v, want %v", tags, want)
+	}
+}
+
+func TestGetRepositoryTagsOptionsMatches(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		opts    GetRepositoryTagsOptions
+		tag     string
+		want    bool
+		wantErr bool
+	}{
+		{"no filter matches everything", GetRepositoryTagsOptions{}, "anything", true, false},
+		{"regexp filter matches", GetRepositoryTagsOptions{Filter: regexp.MustCompile(`^v1\.`)}, "v1.2.3", true, false},
+		{"regexp filter rejects", GetRepositoryTagsOptions{Filter: regexp.MustCompile(`^v1\.`)}, "v2.0.0", false, false},
+		{"glob matches", GetRepositoryTagsOptions{Glob: "v1.*"}, "v1.2.3", true, false},
+		{"glob rejects", GetRepositoryTagsOptions{Glob: "v1.*"}, "v2.0.0", false, false},
+		{"regexp and glob both must match", GetRepositoryTagsOptions{Filter: regexp.MustCompile(`rc`), Glob: "v1.*"}, "v1.2.3-rc1", true, false},
+		{"regexp matches but glob doesn't", GetRepositoryTagsOptions{Filter: regexp.MustCompile(`rc`), Glob: "v2.*"}, "v1.2.3-rc1", false, false},
+		{"invalid glob pattern errors", GetRepositoryTagsOptions{Glob: "["}, "v1.2.3", false, true},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := c.opts.matches(c.tag)
+			if (err != nil) != c.wantErr {
+				t.Fatalf("matches(%q) error = %v, wantErr %v", c.tag, err, c.wantErr)
+			}
+			if err == nil && got != c.want {
+				t.Errorf("matches(%q) = %v, want %v", c.tag, got, c.want)
+			}
+		})
+	}
+}
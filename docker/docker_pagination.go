@@ -0,0 +1,30 @@
+package docker
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+
+	"github.com/pkg/errors"
+)
+
+// linkHeaderNextRegexp matches the RFC 5988 Link header value the distribution registries
+// return for pagination, e.g. `<https://host/v2/foo/tags/list?n=50&last=bar>; rel="next"`.
+var linkHeaderNextRegexp = regexp.MustCompile(`\A<(.+)>;(.+)\z`)
+
+// nextPageFromLinkHeader parses the Link header of a paginated /v2/... response and returns the
+// request path for the next page. ok is false if linkValue indicates there is no further page.
+func nextPageFromLinkHeader(linkValue []string) (nextPath string, ok bool, err error) {
+	if len(linkValue) < 1 {
+		return "", false, nil
+	}
+	match := linkHeaderNextRegexp.FindStringSubmatch(linkValue[0])
+	if match == nil {
+		return "", false, errors.Errorf("Could not parse link header in response: %q", linkValue[0])
+	}
+	u, err := url.Parse(match[1])
+	if err != nil {
+		return "", false, err
+	}
+	return fmt.Sprintf("%s?%s", u.Path, u.RawQuery), true, nil
+}
@@ -0,0 +1,36 @@
+package docker
+
+import "testing"
+
+func TestNextPageFromLinkHeader(t *testing.T) {
+	t.Run("no Link header", func(t *testing.T) {
+		next, ok, err := nextPageFromLinkHeader(nil)
+		if err != nil || ok || next != "" {
+			t.Errorf("nextPageFromLinkHeader(nil) = (%q, %v, %v), want (\"\", false, nil)", next, ok, err)
+		}
+	})
+
+	t.Run("well-formed Link header", func(t *testing.T) {
+		linkValue := []string{`<https://example.com/v2/foo/tags/list?n=50&last=bar>; rel="next"`}
+		next, ok, err := nextPageFromLinkHeader(linkValue)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !ok {
+			t.Fatal("ok = false, want true")
+		}
+		if want := "/v2/foo/tags/list?n=50&last=bar"; next != want {
+			t.Errorf("next = %q, want %q", next, want)
+		}
+	})
+
+	t.Run("malformed Link header", func(t *testing.T) {
+		_, ok, err := nextPageFromLinkHeader([]string{"not a link header"})
+		if err == nil {
+			t.Fatal("expected an error for a malformed Link header")
+		}
+		if ok {
+			t.Error("ok = true, want false")
+		}
+	})
+}
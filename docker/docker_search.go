@@ -0,0 +1,199 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sync"
+
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+
+	"github.com/containers/image/types"
+)
+
+// defaultSearchWorkers is the number of repositories SearchRegistry scans concurrently when
+// types.SystemContext.DockerSearchWorkers is unset.
+const defaultSearchWorkers = 4
+
+// SearchQuery describes what SearchRegistry is looking for.
+type SearchQuery struct {
+	// Pattern is matched against each repository name and each tag within it; a hit is
+	// reported whenever it matches either one.
+	Pattern *regexp.Regexp
+	// MaxResults caps the number of hits returned. Zero means unlimited.
+	MaxResults int
+}
+
+// SearchHit identifies a single tag within the registry that matched a SearchQuery.
+type SearchHit struct {
+	Repository string
+	Tag        string
+	Digest     digest.Digest
+}
+
+// SearchRegistry scans registry's repository catalog and tags for matches against query, using
+// up to sys.DockerSearchWorkers repositories concurrently (defaultSearchWorkers if unset).
+func SearchRegistry(ctx context.Context, sys *types.SystemContext, registry string, query SearchQuery) ([]SearchHit, error) {
+	if query.Pattern == nil {
+		return nil, errors.New("SearchQuery.Pattern must be set")
+	}
+
+	c, err := newDockerClient(sys, registry)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error establishing connection to registry %s", registry)
+	}
+
+	workers := defaultSearchWorkers
+	if sys != nil && sys.DockerSearchWorkers > 0 {
+		workers = sys.DockerSearchWorkers
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		hits     []SearchHit
+		firstErr error
+	)
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+
+	recordErr := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	scanRepo := func(repo string) {
+		defer wg.Done()
+		defer func() { <-sem }()
+
+		repoMatches := query.Pattern.MatchString(repo)
+
+		path := fmt.Sprintf(tagsPath, repo)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+
+			tags, linkValue, err := makeTagsListRequest(ctx, c, path)
+			if err != nil {
+				recordErr(err)
+				return
+			}
+
+			for _, tag := range tags {
+				if !repoMatches && !query.Pattern.MatchString(tag) {
+					continue
+				}
+
+				mu.Lock()
+				if query.MaxResults > 0 && len(hits) >= query.MaxResults {
+					mu.Unlock()
+					cancel()
+					return
+				}
+				mu.Unlock()
+
+				// A single tag's HEAD resolve failing (deleted mid-scan, registry quirk)
+				// shouldn't sink a registry-wide search; skip it and keep going.
+				info, err := headManifestForTag(ctx, c, repo, tag, ErrManifestHeadUnsupported)
+				if err != nil {
+					continue
+				}
+
+				mu.Lock()
+				if query.MaxResults > 0 && len(hits) >= query.MaxResults {
+					mu.Unlock()
+					cancel()
+					return
+				}
+				hits = append(hits, SearchHit{Repository: repo, Tag: tag, Digest: info.Digest})
+				mu.Unlock()
+			}
+
+			next, ok, err := nextPageFromLinkHeader(linkValue)
+			if err != nil {
+				recordErr(errors.Wrapf(err, "fetching tags list for %s", repo))
+				return
+			}
+			if !ok {
+				return
+			}
+			path = next
+		}
+	}
+
+	err = ListRepositoriesIterate(ctx, sys, registry, nil, func(repos []string) error {
+		for _, repo := range repos {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+
+			mu.Lock()
+			full := query.MaxResults > 0 && len(hits) >= query.MaxResults
+			mu.Unlock()
+			if full {
+				return nil
+			}
+
+			wg.Add(1)
+			sem <- struct{}{}
+			go scanRepo(repo)
+		}
+		return nil
+	})
+
+	wg.Wait()
+
+	// Reaching MaxResults cancels ctx to stop the remaining work; errors that this cancellation
+	// causes in requests still in flight do not fail the search.
+	if query.MaxResults > 0 && len(hits) >= query.MaxResults {
+		return hits[:query.MaxResults], nil
+	}
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err != nil && errors.Cause(err) != context.Canceled {
+		return nil, err
+	}
+
+	return hits, nil
+}
+
+// makeTagsListRequest issues a single GET request against path and returns the tag names and
+// any Link header found. It mirrors MakeRepositoryTagsRequestWithContext but takes a
+// *dockerClient rather than an *Image.
+func makeTagsListRequest(ctx context.Context, c *dockerClient, path string) ([]string, []string, error) {
+	type tagsRes struct {
+		Tags []string
+	}
+	tags := &tagsRes{}
+
+	res, err := c.makeRequest(ctx, "GET", path, nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, nil, errors.Errorf("Invalid status code returned when fetching tags list %d", res.StatusCode)
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(tags); err != nil {
+		return nil, nil, err
+	}
+
+	return tags.Tags, (res.Header)["Link"], nil
+}
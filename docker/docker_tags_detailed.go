@@ -0,0 +1,137 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/containers/image/docker/reference"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+// defaultTagDetailWorkers is the number of concurrent manifest HEAD requests
+// GetRepositoryTagsDetailed issues when types.SystemContext.DockerTagDetailWorkers is unset.
+const defaultTagDetailWorkers = 4
+
+// ErrManifestHeadUnsupported is returned when a registry responds 405 to a manifest HEAD
+// request that isn't part of a delete (see ErrRegistryDeleteDisabled for that case).
+var ErrManifestHeadUnsupported = errors.New("registry does not support HEAD requests for manifests")
+
+// TagInfo describes a single tag together with the metadata of the manifest it currently
+// points to. Fields the registry does not supply in its HEAD response are left at their zero
+// value. If resolving this one tag failed, Err is set and every field but Name is left zero.
+type TagInfo struct {
+	Name         string
+	Digest       digest.Digest
+	MediaType    string
+	LastModified time.Time
+	Err          error
+}
+
+// GetRepositoryTagsDetailed lists all tags available in the repository, the same as
+// GetRepositoryTagsWithContext, and additionally resolves each tag's manifest digest, media
+// type and last-modified time with a HEAD request per tag. The number of HEAD requests issued
+// concurrently is bounded by sys.DockerTagDetailWorkers (defaultTagDetailWorkers if unset). A
+// tag whose HEAD request fails gets a TagInfo with just Name and Err set, rather than failing
+// the rest of the batch.
+func (i *Image) GetRepositoryTagsDetailed(ctx context.Context) ([]TagInfo, error) {
+	tags, err := i.GetRepositoryTagsWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workers := defaultTagDetailWorkers
+	if sys := i.src.c.sys; sys != nil && sys.DockerTagDetailWorkers > 0 {
+		workers = sys.DockerTagDetailWorkers
+	}
+
+	result := make([]TagInfo, len(tags))
+	runBounded(workers, len(tags), func(idx int) {
+		info, err := i.headManifest(ctx, tags[idx])
+		if err != nil {
+			result[idx] = TagInfo{Name: tags[idx], Err: err}
+			return
+		}
+		result[idx] = info
+	})
+
+	return result, nil
+}
+
+// runBounded calls fn(idx) once for each idx in [0, n), running at most workers calls
+// concurrently, and returns once every call has finished.
+func runBounded(workers, n int, fn func(idx int)) {
+	if workers < 1 {
+		workers = 1
+	}
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for idx := 0; idx < n; idx++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(idx int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(idx)
+		}(idx)
+	}
+	wg.Wait()
+}
+
+// headManifest issues a HEAD request for the manifest of tag and parses whatever metadata the
+// registry reports about it, degrading gracefully for fields it omits.
+func (i *Image) headManifest(ctx context.Context, tag string) (TagInfo, error) {
+	return headManifestForTag(ctx, i.src.c, reference.Path(i.src.ref.ref), tag, ErrManifestHeadUnsupported)
+}
+
+// headManifestForTag issues a HEAD request for the manifest of tag in repoPath and parses
+// whatever metadata the registry reports about it, degrading gracefully for fields it omits.
+// notAllowedErr is returned for a 405 response; callers resolving a tag before deleting it pass
+// ErrRegistryDeleteDisabled, everyone else passes ErrManifestHeadUnsupported.
+func headManifestForTag(ctx context.Context, c *dockerClient, repoPath string, tag string, notAllowedErr error) (TagInfo, error) {
+	info := TagInfo{Name: tag}
+
+	path := fmt.Sprintf(manifestPath, repoPath, tag)
+	res, err := c.makeRequest(ctx, "HEAD", path, nil, nil)
+	if err != nil {
+		return TagInfo{}, err
+	}
+	defer res.Body.Close()
+	if err := classifyHeadManifestStatus(res.StatusCode, tag, notAllowedErr); err != nil {
+		return TagInfo{}, err
+	}
+
+	if d := res.Header.Get("Docker-Content-Digest"); d != "" {
+		parsed, err := digest.Parse(d)
+		if err != nil {
+			return TagInfo{}, errors.Wrapf(err, "parsing Docker-Content-Digest for tag %q", tag)
+		}
+		info.Digest = parsed
+	}
+	info.MediaType = res.Header.Get("Content-Type")
+	if lm := res.Header.Get("Last-Modified"); lm != "" {
+		if t, err := http.ParseTime(lm); err == nil {
+			info.LastModified = t
+		}
+	}
+
+	return info, nil
+}
+
+// classifyHeadManifestStatus maps a manifest HEAD response status to the error it means, or nil
+// if the manifest was found; notAllowedErr is returned for HTTP 405.
+func classifyHeadManifestStatus(statusCode int, tag string, notAllowedErr error) error {
+	switch statusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return ErrManifestNotFound
+	case http.StatusMethodNotAllowed:
+		return notAllowedErr
+	default:
+		return errors.Errorf("Invalid status code returned when fetching manifest for tag %q: %d", tag, statusCode)
+	}
+}
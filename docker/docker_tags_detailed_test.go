@@ -0,0 +1,59 @@
+package docker
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunBoundedRespectsWorkerCap(t *testing.T) {
+	const n = 50
+	const workers = 4
+
+	var inFlight, maxInFlight int32
+	var mu sync.Mutex
+
+	runBounded(workers, n, func(idx int) {
+		cur := atomic.AddInt32(&inFlight, 1)
+		mu.Lock()
+		if cur > maxInFlight {
+			maxInFlight = cur
+		}
+		mu.Unlock()
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+	})
+
+	if maxInFlight > workers {
+		t.Errorf("observed %d calls in flight at once, want at most %d", maxInFlight, workers)
+	}
+	if maxInFlight < 2 {
+		t.Errorf("observed only %d call in flight at once, expected runBounded to parallelize at all", maxInFlight)
+	}
+}
+
+func TestRunBoundedCallsEveryIndexOnce(t *testing.T) {
+	const n = 20
+	seen := make([]int32, n)
+
+	runBounded(3, n, func(idx int) {
+		atomic.AddInt32(&seen[idx], 1)
+	})
+
+	for idx, count := range seen {
+		if count != 1 {
+			t.Errorf("index %d called %d times, want exactly 1", idx, count)
+		}
+	}
+}
+
+func TestRunBoundedZeroWorkersStillRuns(t *testing.T) {
+	var calls int32
+	runBounded(0, 5, func(idx int) {
+		atomic.AddInt32(&calls, 1)
+	})
+	if calls != 5 {
+		t.Errorf("got %d calls, want 5", calls)
+	}
+}
@@ -0,0 +1,17 @@
+// Package types defines types shared across the various container image transports in this
+// module, most notably SystemContext, which callers use to override each transport's defaults.
+package types
+
+// SystemContext carries optional, transport-specific overrides. Each transport contributes the
+// fields it needs; a zero SystemContext (or a nil *SystemContext) means "use the defaults".
+// Only the fields the docker transport relies on are declared in this checkout.
+type SystemContext struct {
+	// DockerTagDetailWorkers bounds the number of concurrent manifest HEAD requests that
+	// Image.GetRepositoryTagsDetailed issues while resolving per-tag metadata. Zero or
+	// negative means the docker package picks its own default.
+	DockerTagDetailWorkers int
+
+	// DockerSearchWorkers bounds the number of repositories docker.SearchRegistry scans
+	// concurrently. Zero or negative means the docker package picks its own default.
+	DockerSearchWorkers int
+}